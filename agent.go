@@ -0,0 +1,84 @@
+package gllm
+
+import (
+	"context"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// Agent bundles a system prompt, tool set, and default call options so
+// callers don't have to rebuild StructuredRequest boilerplate for every
+// call. Construct one per recurring task (e.g. a code reviewer or SQL
+// planner) and reuse it across calls.
+type Agent struct {
+	Name   string
+	Model  string
+	System string
+
+	Tools        []*gollama.Tool
+	MaxToolCalls int
+
+	// Think sets the default thinking mode for calls made through this
+	// agent. See StructuredRequest.Think.
+	Think *bool
+
+	// PromptOverride sets agent-level prompt template overrides. Per-call
+	// overrides passed to CallWithOverrides are layered on top of these.
+	PromptOverride map[string]string
+}
+
+func (a *Agent) request(context_ string, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return a.PromptOverride
+	}
+
+	merged := make(map[string]string, len(a.PromptOverride)+len(overrides))
+	for k, v := range a.PromptOverride {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Call runs a structured request through the agent's system prompt, tools,
+// and defaults, with context as the task-specific input.
+func Call[T any](a *Agent, c *Client, ctx context.Context, context_ string) (*Response[T], error) {
+	return CallWithOverrides[T](a, c, ctx, context_, nil)
+}
+
+// CallWithOverrides is like Call, but layers per-call prompt overrides on
+// top of the agent's own PromptOverride.
+func CallWithOverrides[T any](a *Agent, c *Client, ctx context.Context, context_ string, overrides map[string]string) (*Response[T], error) {
+	req := &StructuredRequest[T]{
+		Model:          a.Model,
+		System:         a.System,
+		Context:        context_,
+		Tools:          a.Tools,
+		MaxToolCalls:   a.MaxToolCalls,
+		Think:          a.Think,
+		PromptOverride: a.request(context_, overrides),
+	}
+
+	return ModelCallStructured(c, ctx, req)
+}
+
+// CallBatch builds one StructuredRequest per context string using the
+// agent's defaults, and submits them as a single batch.
+func CallBatch[T any](a *Agent, c *Client, ctx context.Context, contexts []string) (*BatchResponse[T], error) {
+	var reqs []*StructuredRequest[T]
+	for _, taskContext := range contexts {
+		reqs = append(reqs, &StructuredRequest[T]{
+			Model:          a.Model,
+			System:         a.System,
+			Context:        taskContext,
+			Tools:          a.Tools,
+			MaxToolCalls:   a.MaxToolCalls,
+			Think:          a.Think,
+			PromptOverride: a.PromptOverride,
+		})
+	}
+
+	return ModelCallStructuredBatch(c, ctx, a.Model, reqs)
+}
@@ -0,0 +1,91 @@
+package gllm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// fakeAgentProvider records the last request it received and always answers
+// with a fixed JSON payload, so Agent's request-building can be exercised
+// without a real backend.
+type fakeAgentProvider struct {
+	lastReq gollama.RequestOptions
+}
+
+func (f *fakeAgentProvider) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	f.lastReq = req
+	return &gollama.ResponseMessageGenerate{
+		Choices: []gollama.GenChoice{{Message: gollama.Message{Role: "assistant", Content: `{"answer":"done"}`}}},
+	}, nil
+}
+
+func (f *fakeAgentProvider) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeAgentProvider) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeAgentProvider) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeAgentProvider) SupportsBatch() bool       { return false }
+func (f *fakeAgentProvider) SupportsThinking() bool    { return false }
+func (f *fakeAgentProvider) SupportsNativeTools() bool { return false }
+
+type agentTestOutput struct {
+	Answer string `json:"answer"`
+}
+
+func TestAgentCallUsesAgentDefaults(t *testing.T) {
+	provider := &fakeAgentProvider{}
+	a := &Agent{
+		Model:        "test-model",
+		System:       "be helpful",
+		MaxToolCalls: 2,
+	}
+
+	resp, err := Call[agentTestOutput](a, NewClientWithProvider(provider), context.Background(), "some context")
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+
+	if provider.lastReq.Model != a.Model {
+		t.Errorf("Model = %q, want %q", provider.lastReq.Model, a.Model)
+	}
+	if provider.lastReq.System != a.System {
+		t.Errorf("System = %q, want %q", provider.lastReq.System, a.System)
+	}
+	if resp.Output == nil || resp.Output.Answer != "done" {
+		t.Fatalf("Output = %+v, want Answer = \"done\"", resp.Output)
+	}
+}
+
+func TestAgentRequestMergesPromptOverrides(t *testing.T) {
+	a := &Agent{PromptOverride: map[string]string{PromptTypeStructuredCall: "base", "keep": "agent"}}
+
+	merged := a.request("ctx", map[string]string{PromptTypeStructuredCall: "override"})
+
+	if merged[PromptTypeStructuredCall] != "override" {
+		t.Errorf("%s = %q, want the per-call override to win", PromptTypeStructuredCall, merged[PromptTypeStructuredCall])
+	}
+	if merged["keep"] != "agent" {
+		t.Errorf("keep = %q, want the agent-level value to survive untouched", merged["keep"])
+	}
+}
+
+func TestAgentRequestFallsBackToAgentOverridesWhenNoneGiven(t *testing.T) {
+	a := &Agent{PromptOverride: map[string]string{"x": "y"}}
+
+	merged := a.request("ctx", nil)
+
+	if !reflect.DeepEqual(merged, a.PromptOverride) {
+		t.Errorf("merged = %v, want a.PromptOverride unchanged: %v", merged, a.PromptOverride)
+	}
+}
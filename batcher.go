@@ -0,0 +1,256 @@
+package gllm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// BatcherOptions configures a Batcher.
+type BatcherOptions struct {
+	// MaxBatchBytes caps the estimated size (via StructuredRequest.EstimateRequestSize)
+	// of a pending group before it's flushed. Defaults to 32MB if zero.
+	MaxBatchBytes int
+
+	// FlushInterval flushes any pending group that hasn't hit MaxBatchBytes
+	// within this duration. Defaults to 5s if zero.
+	FlushInterval time.Duration
+
+	// PollInterval controls how often a submitted batch's status is
+	// checked. Defaults to 5s if zero.
+	PollInterval time.Duration
+
+	// PollConcurrency caps how many batches are polled concurrently.
+	// Defaults to 4 if zero.
+	PollConcurrency int
+}
+
+// Batcher wraps a Client and coalesces concurrent Submit calls into batch
+// API submissions, grouped by (model, system prompt). Callers get a
+// per-request channel for their result without giving up the batch API's
+// pricing/throughput, at the cost of added latency while a group fills.
+type Batcher[T any] struct {
+	client *Client
+	opts   BatcherOptions
+
+	pollSem chan struct{}
+
+	// closeCh is closed by Close, which unblocks any poll loop waiting on
+	// a batch that never reaches "ended" so Close can't hang forever.
+	closeCh   chan struct{}
+	closeOnce sync.Once
+
+	mu     sync.Mutex
+	groups map[string]*batchGroup[T]
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type batchGroup[T any] struct {
+	// ctx is the context of whichever Submit call first created this
+	// group. Polling is cancelled if it's done; later Submit calls into
+	// the same group don't get their own cancellation, only this one.
+	ctx context.Context
+
+	model    string
+	requests []*StructuredRequest[T]
+	chans    []chan *BatchResult[T]
+	bytes    int
+	timer    *time.Timer
+}
+
+// NewBatcher builds a Batcher around c, applying default options for any
+// zero-valued fields in opts.
+func NewBatcher[T any](c *Client, opts BatcherOptions) *Batcher[T] {
+	if opts.MaxBatchBytes == 0 {
+		opts.MaxBatchBytes = 32 << 20
+	}
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.PollConcurrency == 0 {
+		opts.PollConcurrency = 4
+	}
+
+	return &Batcher[T]{
+		client:  c,
+		opts:    opts,
+		pollSem: make(chan struct{}, opts.PollConcurrency),
+		closeCh: make(chan struct{}),
+		groups:  make(map[string]*batchGroup[T]),
+	}
+}
+
+// groupKey identifies requests that can share a batch submission: same
+// model and system prompt.
+func groupKey(model, system string) string {
+	h := sha256.Sum256([]byte(system))
+	return model + "|" + hex.EncodeToString(h[:])
+}
+
+// Submit enqueues req into its (model, system prompt) group, flushing the
+// group immediately if MaxBatchBytes is reached, and returns a channel that
+// receives req's result once its batch completes. The channel is closed
+// after the single result is sent.
+func (b *Batcher[T]) Submit(ctx context.Context, req *StructuredRequest[T]) (<-chan *BatchResult[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	size, err := req.EstimateRequestSize()
+	if err != nil {
+		return nil, fmt.Errorf("estimating request size: %w", err)
+	}
+
+	resultCh := make(chan *BatchResult[T], 1)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, fmt.Errorf("batcher is closed")
+	}
+
+	key := groupKey(req.Model, req.System)
+	g, ok := b.groups[key]
+	if !ok {
+		g = &batchGroup[T]{ctx: ctx, model: req.Model}
+		g.timer = time.AfterFunc(b.opts.FlushInterval, func() {
+			b.flush(key)
+		})
+		b.groups[key] = g
+	}
+
+	g.requests = append(g.requests, req)
+	g.chans = append(g.chans, resultCh)
+	g.bytes += size
+
+	shouldFlush := g.bytes >= b.opts.MaxBatchBytes
+	b.mu.Unlock()
+
+	if shouldFlush {
+		b.flush(key)
+	}
+
+	return resultCh, nil
+}
+
+// flush submits whatever is pending for key as a batch, then polls for
+// results in the background and fans them out to each caller's channel.
+func (b *Batcher[T]) flush(key string) {
+	b.mu.Lock()
+	g, ok := b.groups[key]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.groups, key)
+	b.mu.Unlock()
+
+	g.timer.Stop()
+
+	if len(g.requests) == 0 {
+		return
+	}
+
+	b.wg.Add(1)
+	go b.submitAndPoll(g)
+}
+
+func (b *Batcher[T]) submitAndPoll(g *batchGroup[T]) {
+	defer b.wg.Done()
+
+	fail := func(err error) {
+		for _, ch := range g.chans {
+			ch <- &BatchResult[T]{
+				ResultType: "errored",
+				Error:      &gollama.BatchError{Type: "batcher_error", Message: err.Error()},
+			}
+			close(ch)
+		}
+	}
+
+	batchResp, err := ModelCallStructuredBatch(b.client, g.ctx, g.model, g.requests)
+	if err != nil {
+		fail(fmt.Errorf("submitting batch: %w", err))
+		return
+	}
+
+	b.pollSem <- struct{}{}
+	defer func() { <-b.pollSem }()
+
+	for {
+		// Close dispatches flush then immediately closes closeCh, so a
+		// batch that's already ended the moment it's submitted (fast
+		// backends, or one last check right before Close returns) must not
+		// wait on the select below: doing so would race closeCh and report
+		// a spurious "abandoned" error for a batch that actually finished.
+		if batchResp.Status != "ended" {
+			select {
+			case <-time.After(b.opts.PollInterval):
+			case <-g.ctx.Done():
+				fail(fmt.Errorf("batch %s abandoned: %w", batchResp.BatchID, g.ctx.Err()))
+				return
+			case <-b.closeCh:
+				fail(fmt.Errorf("batcher closed while batch %s was still pending", batchResp.BatchID))
+				return
+			}
+		}
+
+		batchResp, err = GetModelCallBatchResults[T](b.client, g.ctx, batchResp.BatchID)
+		if err != nil {
+			fail(fmt.Errorf("polling batch: %w", err))
+			return
+		}
+
+		if batchResp.Status == "ended" {
+			break
+		}
+	}
+
+	// batch request index i corresponds to custom ID "request-%d", which
+	// in turn corresponds to g.requests[i] / g.chans[i].
+	resultByCustomID := make(map[string]*BatchResult[T], len(batchResp.Results))
+	for _, r := range batchResp.Results {
+		resultByCustomID[r.CustomID] = r
+	}
+
+	for i, ch := range g.chans {
+		customID := fmt.Sprintf("request-%d", i)
+		if r, ok := resultByCustomID[customID]; ok {
+			ch <- r
+		} else {
+			ch <- &BatchResult[T]{ResultType: "errored", Error: &gollama.BatchError{Type: "batcher_error", Message: "no result for " + customID}}
+		}
+		close(ch)
+	}
+}
+
+// Close flushes all pending groups and waits for their batches to
+// resolve before returning. Once called, any batch that's still polling
+// is told to give up immediately (reporting an error on its pending
+// result channels) rather than left to poll forever, so Close itself
+// can't hang on a stuck or abandoned batch.
+func (b *Batcher[T]) Close() {
+	b.mu.Lock()
+	b.closed = true
+	keys := make([]string, 0, len(b.groups))
+	for k := range b.groups {
+		keys = append(keys, k)
+	}
+	b.mu.Unlock()
+
+	for _, k := range keys {
+		b.flush(k)
+	}
+
+	b.closeOnce.Do(func() { close(b.closeCh) })
+	b.wg.Wait()
+}
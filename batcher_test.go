@@ -0,0 +1,180 @@
+package gllm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+func TestGroupKeyDeterministic(t *testing.T) {
+	if groupKey("gpt", "sys a") != groupKey("gpt", "sys a") {
+		t.Error("groupKey should be deterministic for identical inputs")
+	}
+	if groupKey("gpt", "sys a") == groupKey("gpt", "sys b") {
+		t.Error("groupKey should differ for different system prompts")
+	}
+	if groupKey("gpt-a", "sys") == groupKey("gpt-b", "sys") {
+		t.Error("groupKey should differ for different models")
+	}
+}
+
+// fakeBatchProvider is a minimal Provider that completes every batch
+// immediately (unless neverEnds is set, in which case it stays
+// "in_progress" forever), so Batcher's grouping/flush logic can be
+// exercised without a real backend.
+type fakeBatchProvider struct {
+	mu         sync.Mutex
+	batchCalls []gollama.CreateBatchRequest
+	neverEnds  bool
+}
+
+func (f *fakeBatchProvider) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeBatchProvider) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batchCalls = append(f.batchCalls, req)
+	return &gollama.Batch{
+		ID:               fmt.Sprintf("batch-%d", len(f.batchCalls)),
+		ProcessingStatus: f.status(),
+	}, nil
+}
+
+func (f *fakeBatchProvider) status() string {
+	if f.neverEnds {
+		return "in_progress"
+	}
+	return "ended"
+}
+
+func (f *fakeBatchProvider) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &gollama.Batch{ID: batchID, ProcessingStatus: f.status()}, nil
+}
+
+func (f *fakeBatchProvider) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var idx int
+	for i := range f.batchCalls {
+		if fmt.Sprintf("batch-%d", i+1) == batchID {
+			idx = i
+			break
+		}
+	}
+
+	results := make([]gollama.BatchResult, len(f.batchCalls[idx].Requests))
+	for i, r := range f.batchCalls[idx].Requests {
+		results[i] = gollama.BatchResult{
+			CustomID: r.CustomID,
+			Result: gollama.BatchResultDetail{
+				Type: "succeeded",
+				Message: &gollama.BatchMessageResult{
+					Content: []gollama.BatchContentBlock{{Type: "text", Text: `{"n":1}`}},
+				},
+			},
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeBatchProvider) SupportsBatch() bool       { return true }
+func (f *fakeBatchProvider) SupportsThinking() bool    { return false }
+func (f *fakeBatchProvider) SupportsNativeTools() bool { return false }
+
+type batcherTestOutput struct {
+	N int `json:"n"`
+}
+
+func TestBatcherGroupsRequestsBySameModelAndSystem(t *testing.T) {
+	fake := &fakeBatchProvider{}
+	c := NewClientWithProvider(fake)
+	b := NewBatcher[batcherTestOutput](c, BatcherOptions{
+		FlushInterval: time.Hour, // never fires on its own during the test
+		PollInterval:  10 * time.Millisecond,
+	})
+
+	ctx := context.Background()
+	req := func(model, system string) *StructuredRequest[batcherTestOutput] {
+		return &StructuredRequest[batcherTestOutput]{Model: model, System: system, Prompt: "p"}
+	}
+
+	ch1, err := b.Submit(ctx, req("m1", "sys"))
+	if err != nil {
+		t.Fatalf("Submit 1: %v", err)
+	}
+	ch2, err := b.Submit(ctx, req("m1", "sys"))
+	if err != nil {
+		t.Fatalf("Submit 2: %v", err)
+	}
+	ch3, err := b.Submit(ctx, req("m2", "sys"))
+	if err != nil {
+		t.Fatalf("Submit 3: %v", err)
+	}
+
+	b.Close()
+
+	r1 := <-ch1
+	r2 := <-ch2
+	r3 := <-ch3
+	for i, r := range []*BatchResult[batcherTestOutput]{r1, r2, r3} {
+		if r.Output == nil || r.Output.N != 1 {
+			t.Errorf("result %d = %+v, want Output.N == 1", i, r)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.batchCalls) != 2 {
+		t.Fatalf("CreateBatch called %d times, want 2 (one per distinct model/system group)", len(fake.batchCalls))
+	}
+	// Close flushes groups in map iteration order, so which group becomes
+	// the first/second batch isn't deterministic; just check the sizes.
+	sizes := []int{len(fake.batchCalls[0].Requests), len(fake.batchCalls[1].Requests)}
+	if !((sizes[0] == 2 && sizes[1] == 1) || (sizes[0] == 1 && sizes[1] == 2)) {
+		t.Errorf("batch sizes = %v, want one batch of 2 (m1/sys) and one of 1 (m2/sys)", sizes)
+	}
+}
+
+func TestBatcherCloseUnblocksStuckPoll(t *testing.T) {
+	fake := &fakeBatchProvider{neverEnds: true}
+	c := NewClientWithProvider(fake)
+	b := NewBatcher[batcherTestOutput](c, BatcherOptions{
+		FlushInterval: time.Hour,
+		PollInterval:  time.Hour,
+	})
+
+	ctx := context.Background()
+	ch, err := b.Submit(ctx, &StructuredRequest[batcherTestOutput]{Model: "m1", System: "sys", Prompt: "p"})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return; it should give up on a stuck poll instead of hanging")
+	}
+
+	select {
+	case r := <-ch:
+		if r.Error == nil {
+			t.Error("expected an error result for a batch abandoned by Close")
+		}
+	default:
+		t.Error("expected a result to already be available on ch once Close returned")
+	}
+}
@@ -36,40 +36,101 @@ type StructuredRequest[T any] struct {
 	MaxToolCalls int
 	Tools        []*gollama.Tool
 
+	// AutoExecuteTools controls whether requested tool calls are run
+	// automatically via gollama.HandleToolCall. Defaults to true for
+	// backwards compatibility; set to false to have ModelCallStructured
+	// return with Response.PendingToolCalls populated instead, so the
+	// caller can drive approval/execution itself.
+	AutoExecuteTools *bool
+
+	// ApproveToolCall, if set, is called before each tool call is executed
+	// (only when AutoExecuteTools is true). Returning approve=false skips
+	// execution and feeds overrideResult back to the model as the tool's
+	// result instead, letting callers prompt a user, audit, sandbox, or
+	// fabricate a result.
+	ApproveToolCall func(ctx context.Context, toolName, argsJSON string) (approve bool, overrideResult string, err error)
+
+	// OnToolCall, if set, is called for each tool call that is executed or
+	// vetoed, after approval is decided, for observability/auditing
+	// purposes. It is not called when AutoExecuteTools is false, nor for
+	// tool calls left unprocessed once MaxToolCalls runs out.
+	OnToolCall func(event ToolCallEvent)
+
 	PromptOverride map[string]string
 
 	// Think enables extended thinking/reasoning mode if supported by the model.
 	// When nil, defaults to true for backwards compatibility.
 	// Use BoolPtr(false) to disable thinking.
 	Think *bool
+
+	// MaxRepairAttempts controls self-healing of malformed JSON output. On
+	// a json.Unmarshal failure, the model is re-prompted with the exact
+	// unmarshal error and the output spec (T's JSON schema, or its
+	// customOutputSpec description) and asked to emit
+	// a corrected JSON object, up to this many times. Repair round-trips
+	// are counted separately from MaxToolCalls. Zero (the default)
+	// preserves the old behavior of failing immediately.
+	MaxRepairAttempts int
 }
 
-// BoolPtr returns a pointer to a bool value, useful for setting Think field
-func BoolPtr(b bool) *bool {
-	return &b
+// autoExecuteTools reports whether tool calls should be run automatically,
+// defaulting to true for backwards compatibility.
+func (r *StructuredRequest[T]) autoExecuteTools() bool {
+	return r.AutoExecuteTools == nil || *r.AutoExecuteTools
 }
 
-func renderOutputSpec(obj any) (string, error) {
-	if cos, ok := obj.(customOutputSpec); ok {
-		return cos.DescribeType(), nil
-	}
+// ToolCallEvent describes a single tool call made during a structured call,
+// for observability via StructuredRequest.OnToolCall.
+type ToolCallEvent struct {
+	ToolName string
+	ArgsJSON string
+
+	// Approved is false when ApproveToolCall vetoed the call.
+	Approved bool
+
+	// Result is the tool's result (or the ApproveToolCall override) that
+	// was fed back to the model.
+	Result string
 
-	b, err := json.Marshal(obj)
-	return string(b), err
+	// Err is set if ApproveToolCall returned an error or executing the
+	// tool itself failed.
+	Err error
 }
 
-type customOutputSpec interface {
-	DescribeType() string
+// PendingToolCall is a tool call requested by the model that has not yet
+// been executed, returned on Response.PendingToolCalls when
+// StructuredRequest.AutoExecuteTools is false.
+type PendingToolCall struct {
+	ID       string
+	ToolName string
+	ArgsJSON string
 }
 
+// BoolPtr returns a pointer to a bool value, useful for setting Think field
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
+// NewClient wraps an existing *gollama.Client (Anthropic's native API) as
+// the Client's Provider. Existing callers can keep using this constructor
+// unchanged; use NewClientWithProvider to target OpenAI, Google Gemini, or
+// Ollama instead.
 func NewClient(olc *gollama.Client) *Client {
 	return &Client{
-		ollmc: olc,
+		provider: &anthropicProvider{gc: olc},
+	}
+}
+
+// NewClientWithProvider builds a Client around an arbitrary Provider
+// implementation, e.g. one of the backends under ./providers.
+func NewClientWithProvider(p Provider) *Client {
+	return &Client{
+		provider: p,
 	}
 }
 
 type Client struct {
-	ollmc *gollama.Client
+	provider Provider
 
 	// Debug enables debug output. If DebugFunc is nil, uses fmt.Println.
 	Debug bool
@@ -209,6 +270,16 @@ type Response[T any] struct {
 	ModelComment  string
 	RawResponse   *gollama.ResponseMessageGenerate
 	InputMessages []gollama.Message
+
+	// PendingToolCalls is populated instead of Output when
+	// StructuredRequest.AutoExecuteTools is false and the model requested
+	// tool calls. The caller is expected to execute (or approve) them and
+	// resume the conversation via InputMessages.
+	PendingToolCalls []PendingToolCall
+
+	// RepairsUsed is the number of JSON repair round-trips consumed to
+	// produce Output. See StructuredRequest.MaxRepairAttempts.
+	RepairsUsed int
 }
 
 // BatchResponse represents the result of a batch of structured requests
@@ -239,6 +310,7 @@ func ModelCallStructured[T any](c *Client, ctx context.Context, req *StructuredR
 	if err != nil {
 		return nil, err
 	}
+	schema, hasSchema := schemaFor(new(T))
 
 	// system
 	var msgs []gollama.Message
@@ -289,6 +361,8 @@ func ModelCallStructured[T any](c *Client, ctx context.Context, req *StructuredR
 		think = *req.Think
 	}
 
+	repairsUsed := 0
+
 	for {
 		glreq := gollama.RequestOptions{
 			Model:    req.Model,
@@ -307,7 +381,12 @@ func ModelCallStructured[T any](c *Client, ctx context.Context, req *StructuredR
 			c.debugJSON(glreq.Messages)
 		}
 
-		resp, err := c.ollmc.ChatCompletion(glreq)
+		var resp *gollama.ResponseMessageGenerate
+		if sp, ok := c.provider.(SchemaAwareProvider); ok && hasSchema {
+			resp, err = sp.ChatCompletionWithSchema(ctx, glreq, schema)
+		} else {
+			resp, err = c.provider.ChatCompletion(ctx, glreq)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -317,59 +396,141 @@ func ModelCallStructured[T any](c *Client, ctx context.Context, req *StructuredR
 			c.debugJSON(resp)
 		}
 
-		mm := resp.Choices[0].Message
+		var result *Response[T]
+		var done bool
+		result, msgs, repairsUsed, done, err = handleStructuredResponse(ctx, c, req, ospec, resp, msgs, repairsUsed)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return result, nil
+		}
+	}
+}
+
+// handleStructuredResponse is the post-response half of ModelCallStructured
+// and ModelCallStructuredStream: given the completion resp just obtained
+// (by whichever means), it parses a final JSON answer, drives the JSON
+// repair retry, or dispatches/approves tool calls, as needed. The caller
+// loops, feeding the returned msgs/repairsUsed into its next completion
+// request, until done is true (at which point result is the call's final
+// Response, possibly with PendingToolCalls set).
+func handleStructuredResponse[T any](ctx context.Context, c *Client, req *StructuredRequest[T], ospec string, resp *gollama.ResponseMessageGenerate, msgs []gollama.Message, repairsUsed int) (result *Response[T], nextMsgs []gollama.Message, nextRepairsUsed int, done bool, err error) {
+	mm := resp.Choices[0].Message
 
-		if len(mm.ToolCalls) == 0 {
-			output := cleanJsonOutput(resp.Choices[0].Message.Content)
+	if len(mm.ToolCalls) == 0 {
+		output := cleanJsonOutput(mm.Content)
 
-			c.debugf("MODEL OUTPUT:\n%s", output)
+		c.debugf("MODEL OUTPUT:\n%s", output)
 
-			message, jsonout := extractJSONAndComment(output)
+		message, jsonout := extractJSONAndComment(output)
 
-			if message != "" {
-				c.debugf("Model sent a message along with its output: %q", message)
-			}
-			var outv T
-			if err := json.Unmarshal([]byte(jsonout), &outv); err != nil {
-				return nil, fmt.Errorf("failed to parse JSON output: %w (output was: %s)", err, output)
+		if message != "" {
+			c.debugf("Model sent a message along with its output: %q", message)
+		}
+		var outv T
+		if err := json.Unmarshal([]byte(jsonout), &outv); err != nil {
+			if repairsUsed < req.MaxRepairAttempts {
+				repairsUsed++
+				c.debugf("JSON parse failed, requesting repair attempt %d/%d: %v", repairsUsed, req.MaxRepairAttempts, err)
+
+				msgs = append(msgs, mm, gollama.Message{
+					Role: "user",
+					Content: fmt.Sprintf(
+						"Your last response could not be parsed as JSON: %s\n\nHere is the output template again:\n%s\n\nPlease respond with ONLY a corrected JSON object matching the template, starting with the { character.",
+						err, ospec,
+					),
+				})
+				return nil, msgs, repairsUsed, false, nil
 			}
 
-			return &Response[T]{
-				Output:        &outv,
-				ModelComment:  message,
-				RawResponse:   resp,
-				InputMessages: msgs,
-			}, nil
+			return nil, msgs, repairsUsed, false, fmt.Errorf("failed to parse JSON output: %w (output was: %s)", err, output)
 		}
 
-		c.debugf("Model requested %d tool call(s)", len(mm.ToolCalls))
+		return &Response[T]{
+			Output:        &outv,
+			ModelComment:  message,
+			RawResponse:   resp,
+			InputMessages: msgs,
+			RepairsUsed:   repairsUsed,
+		}, msgs, repairsUsed, true, nil
+	}
+
+	c.debugf("Model requested %d tool call(s)", len(mm.ToolCalls))
 
-		// Add the assistant message with tool calls
-		msgs = append(msgs, mm)
+	// Add the assistant message with tool calls
+	msgs = append(msgs, mm)
 
-		// Handle all tool calls
+	if !req.autoExecuteTools() {
+		var pending []PendingToolCall
 		for _, tc := range mm.ToolCalls {
-			c.debugf("Tool call: %s %s", tc.Function.Name, tc.Function.Arguments)
+			pending = append(pending, PendingToolCall{
+				ID:       tc.ID,
+				ToolName: tc.Function.Name,
+				ArgsJSON: tc.Function.Arguments,
+			})
+		}
+
+		return &Response[T]{
+			RawResponse:      resp,
+			InputMessages:    msgs,
+			PendingToolCalls: pending,
+		}, msgs, repairsUsed, true, nil
+	}
+
+	// Handle all tool calls
+	for _, tc := range mm.ToolCalls {
+		c.debugf("Tool call: %s %s", tc.Function.Name, tc.Function.Arguments)
 
-			toolresp, err := gollama.HandleToolCall(ctx, req.Tools, tc)
+		approved := true
+		var toolresp string
+		var err error
+
+		if req.ApproveToolCall != nil {
+			var overrideResult string
+			approved, overrideResult, err = req.ApproveToolCall(ctx, tc.Function.Name, tc.Function.Arguments)
 			if err != nil {
+				toolresp = fmt.Sprintf("Error: %v", err)
+			} else if !approved {
+				toolresp = overrideResult
+			}
+		}
+
+		if approved && err == nil {
+			toolResult, herr := gollama.HandleToolCall(ctx, req.Tools, tc)
+			if herr != nil {
 				// Return error to model instead of failing completely
+				err = herr
 				toolresp = fmt.Sprintf("Error: %v", err)
 				c.debugf("Tool call error (sending to model): %s", toolresp)
+			} else {
+				toolresp = toolResult.Content
 			}
+		}
 
-			msgs = append(msgs, gollama.Message{
-				Role:       "tool",
-				Content:    toolresp,
-				ToolCallID: tc.ID,
+		if req.OnToolCall != nil {
+			req.OnToolCall(ToolCallEvent{
+				ToolName: tc.Function.Name,
+				ArgsJSON: tc.Function.Arguments,
+				Approved: approved,
+				Result:   toolresp,
+				Err:      err,
 			})
+		}
 
-			req.MaxToolCalls--
-			if req.MaxToolCalls <= 0 {
-				break
-			}
+		msgs = append(msgs, gollama.Message{
+			Role:       "tool",
+			Content:    toolresp,
+			ToolCallID: tc.ID,
+		})
+
+		req.MaxToolCalls--
+		if req.MaxToolCalls <= 0 {
+			break
 		}
 	}
+
+	return nil, msgs, repairsUsed, false, nil
 }
 
 func cleanJsonOutput(s string) string {
@@ -404,7 +565,10 @@ func extractJSONAndComment(output string) (string, string) {
 // ModelCallStructuredBatch creates a batch of structured requests and submits them to the API
 // Returns a BatchResponse containing the batch ID and status. Use GetModelCallBatchResults to retrieve results.
 // Note: Tool calling is not supported in batch mode.
-func ModelCallStructuredBatch[T any](c *Client, model string, requests []*StructuredRequest[T]) (*BatchResponse[T], error) {
+func ModelCallStructuredBatch[T any](c *Client, ctx context.Context, model string, requests []*StructuredRequest[T]) (*BatchResponse[T], error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if len(requests) == 0 {
 		return nil, fmt.Errorf("no requests provided")
 	}
@@ -470,7 +634,7 @@ func ModelCallStructuredBatch[T any](c *Client, model string, requests []*Struct
 	}
 
 	// Submit the batch
-	batch, err := c.ollmc.CreateBatch(gollama.CreateBatchRequest{
+	batch, err := c.provider.CreateBatch(ctx, gollama.CreateBatchRequest{
 		Requests: batchRequests,
 	})
 	if err != nil {
@@ -486,9 +650,13 @@ func ModelCallStructuredBatch[T any](c *Client, model string, requests []*Struct
 }
 
 // GetModelCallBatchResults retrieves and parses the results of a completed batch
-func GetModelCallBatchResults[T any](c *Client, batchID string) (*BatchResponse[T], error) {
+func GetModelCallBatchResults[T any](c *Client, ctx context.Context, batchID string) (*BatchResponse[T], error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	// First get the batch status
-	batch, err := c.ollmc.GetBatch(batchID)
+	batch, err := c.provider.GetBatch(ctx, batchID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get batch status: %w", err)
 	}
@@ -506,7 +674,7 @@ func GetModelCallBatchResults[T any](c *Client, batchID string) (*BatchResponse[
 	}
 
 	// Get the results
-	results, err := c.ollmc.GetBatchResults(batchID)
+	results, err := c.provider.GetBatchResults(ctx, batchID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get batch results: %w", err)
 	}
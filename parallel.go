@@ -0,0 +1,176 @@
+package gllm
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how ModelCallStructuredParallel retries a failed
+// call before giving up on it.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Zero or one means no retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry; each subsequent
+	// retry doubles it (capped at MaxDelay), plus jitter.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay.
+	MaxDelay time.Duration
+
+	// ShouldRetry decides whether err is retryable. If nil, every error is
+	// retried (callers filtering 429/5xx should provide their own).
+	ShouldRetry func(err error) bool
+}
+
+func (p RetryPolicy) shouldRetry(err error) bool {
+	if p.ShouldRetry == nil {
+		return true
+	}
+	return p.ShouldRetry(err)
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base == 0 {
+		base = 500 * time.Millisecond
+	}
+	max := p.MaxDelay
+	if max == 0 {
+		max = 30 * time.Second
+	}
+
+	d := base * time.Duration(1<<uint(attempt))
+	if d > max || d <= 0 {
+		d = max
+	}
+
+	// Add up to 20% jitter so retries from concurrent workers don't
+	// stampede in lockstep. rand.Int63n panics on n <= 0, which int64(d)/5
+	// rounds to for any d under 5ns, so floor it at 1.
+	jitterRange := int64(d) / 5
+	if jitterRange <= 0 {
+		jitterRange = 1
+	}
+	jitter := time.Duration(rand.Int63n(jitterRange))
+	return d + jitter
+}
+
+// ParallelOptions configures ModelCallStructuredParallel.
+type ParallelOptions struct {
+	// Concurrency caps how many requests are in flight at once. Defaults
+	// to 4 if zero.
+	Concurrency int
+
+	// RateLimitPerSec, if non-zero, limits the aggregate rate at which new
+	// requests are started, regardless of Concurrency.
+	RateLimitPerSec float64
+
+	// RetryPolicy controls retries on failed calls. The zero value means
+	// no retries.
+	RetryPolicy RetryPolicy
+}
+
+// ModelCallStructuredParallel runs reqs through c concurrently using a
+// bounded worker pool, preserving input order in the returned slices:
+// results[i]/errs[i] correspond to reqs[i]. Cancelling ctx stops any
+// in-flight calls and unstarted ones are skipped.
+func ModelCallStructuredParallel[T any](c *Client, ctx context.Context, reqs []*StructuredRequest[T], opts ParallelOptions) ([]*Response[T], []error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	results := make([]*Response[T], len(reqs))
+	errs := make([]error, len(reqs))
+
+	var limiter *time.Ticker
+	if opts.RateLimitPerSec > 0 {
+		limiter = time.NewTicker(time.Duration(float64(time.Second) / opts.RateLimitPerSec))
+		defer limiter.Stop()
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, req := range reqs {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		if limiter != nil {
+			select {
+			case <-limiter.C:
+			case <-ctx.Done():
+				<-sem
+				errs[i] = ctx.Err()
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, req *StructuredRequest[T]) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i], errs[i] = callWithRetry(c, ctx, req, opts.RetryPolicy)
+		}(i, req)
+	}
+
+	wg.Wait()
+
+	return results, errs
+}
+
+func callWithRetry[T any](c *Client, ctx context.Context, req *StructuredRequest[T], policy RetryPolicy) (*Response[T], error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// ModelCallStructured mutates req.MaxToolCalls as it executes tool
+		// calls, so each attempt needs its own copy: otherwise a call that
+		// makes some tool calls before failing for an unrelated reason
+		// would retry with a permanently lower MaxToolCalls than the
+		// caller configured.
+		attemptReq := *req
+		resp, err := ModelCallStructured(c, ctx, &attemptReq)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if attempt == maxAttempts-1 || !policy.shouldRetry(err) {
+			break
+		}
+
+		select {
+		case <-time.After(policy.delay(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,60 @@
+package gllm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// Nil ShouldRetry means everything is retryable.
+	p := RetryPolicy{}
+	if !p.shouldRetry(errBoom) {
+		t.Error("nil ShouldRetry should retry every error")
+	}
+
+	p.ShouldRetry = func(err error) bool { return false }
+	if p.shouldRetry(errBoom) {
+		t.Error("ShouldRetry returning false should not retry")
+	}
+}
+
+func TestRetryPolicyDelayDefaults(t *testing.T) {
+	p := RetryPolicy{}
+
+	for attempt := 0; attempt < 8; attempt++ {
+		d := p.delay(attempt)
+		if d < 500*time.Millisecond {
+			t.Errorf("delay(%d) = %v, want >= base delay of 500ms", attempt, d)
+		}
+		if d > 30*time.Second+6*time.Second {
+			t.Errorf("delay(%d) = %v, want <= MaxDelay plus jitter", attempt, d)
+		}
+	}
+}
+
+func TestRetryPolicyDelayCapsAtMaxDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	// A large attempt count would overflow BaseDelay*2^attempt; delay must
+	// still land at (or just above, for jitter) MaxDelay rather than
+	// wrapping around to a negative/zero duration.
+	d := p.delay(40)
+	if d < 5*time.Second || d > 6*time.Second {
+		t.Errorf("delay(40) = %v, want within [MaxDelay, MaxDelay*1.2]", d)
+	}
+}
+
+func TestRetryPolicyDelayDoesNotPanicOnTinyBaseDelay(t *testing.T) {
+	// BaseDelay under 5ns makes int64(d)/5 round to 0, which rand.Int63n
+	// would panic on; delay must floor the jitter range instead.
+	p := RetryPolicy{BaseDelay: 1, MaxDelay: time.Second}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		if d := p.delay(attempt); d < 0 {
+			t.Errorf("delay(%d) = %v, want non-negative", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gllm
+
+import (
+	"context"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// Provider abstracts over a concrete LLM backend so that ModelCallStructured
+// and the batch helpers can target more than gollama's built-in Anthropic
+// client. Implementations live under ./providers for OpenAI, Google Gemini,
+// and Ollama; the gollama-backed Anthropic client is wrapped automatically
+// by NewClient so existing callers don't need to change anything.
+//
+// Every method that calls the backend takes ctx first so that cancelling the caller's context
+// actually cancels in-flight network calls, not just the tool-call loop
+// around them.
+type Provider interface {
+	// ChatCompletion issues a single (non-streaming) completion request.
+	ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error)
+
+	// CreateBatch submits a batch of requests for asynchronous processing.
+	CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error)
+
+	// GetBatch returns the current status of a previously submitted batch.
+	GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error)
+
+	// GetBatchResults returns the per-request results of a completed batch.
+	GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error)
+
+	// SupportsBatch reports whether this provider implements the batch API.
+	SupportsBatch() bool
+
+	// SupportsThinking reports whether this provider can be asked to emit
+	// extended thinking/reasoning output.
+	SupportsThinking() bool
+
+	// SupportsNativeTools reports whether this provider has a native
+	// tool-calling surface, as opposed to requiring tool use to be emulated
+	// through prompting.
+	SupportsNativeTools() bool
+}
+
+// anthropicProvider adapts the existing *gollama.Client (Anthropic's native
+// API) to the Provider interface. It's what NewClient wraps requests in, so
+// that existing callers keep working unchanged.
+//
+// gollama.Client's methods predate context support, so ctx is accepted (to
+// satisfy Provider) but not yet forwarded; cancellation still applies at
+// the tool-call loop boundary as before.
+type anthropicProvider struct {
+	gc *gollama.Client
+}
+
+func (p *anthropicProvider) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	return p.gc.ChatCompletion(req)
+}
+
+func (p *anthropicProvider) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return p.gc.CreateBatch(req)
+}
+
+func (p *anthropicProvider) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return p.gc.GetBatch(batchID)
+}
+
+func (p *anthropicProvider) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return p.gc.GetBatchResults(batchID)
+}
+
+func (p *anthropicProvider) SupportsBatch() bool       { return true }
+func (p *anthropicProvider) SupportsThinking() bool    { return true }
+func (p *anthropicProvider) SupportsNativeTools() bool { return true }
+
+// ChatCompletionStream satisfies StreamingProvider by delegating to
+// gollama's own streaming support.
+//
+// gollama.Client's methods predate context support, so ctx is accepted (to
+// satisfy StreamingProvider) but not yet forwarded; cancellation still
+// applies at the tool-call loop boundary as before.
+func (p *anthropicProvider) ChatCompletionStream(ctx context.Context, req gollama.RequestOptions, onDelta func(text string)) (*gollama.ResponseMessageGenerate, error) {
+	return p.gc.TurnStream(req, onDelta)
+}
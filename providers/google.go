@@ -0,0 +1,273 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/whyrusleeping/gllm"
+	"github.com/whyrusleeping/gollama"
+	"google.golang.org/api/option"
+)
+
+// Google adapts the Gemini API to the gllm.Provider interface.
+type Google struct {
+	client *genai.Client
+}
+
+// NewGoogle builds a Google provider from an API key.
+func NewGoogle(ctx context.Context, apiKey string) (*Google, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("creating genai client: %w", err)
+	}
+	return &Google{client: client}, nil
+}
+
+func (p *Google) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	model := p.client.GenerativeModel(req.Model)
+
+	if req.System != "" {
+		model.SystemInstruction = genai.NewUserContent(genai.Text(req.System))
+	}
+
+	for _, t := range req.Tools {
+		model.Tools = append(model.Tools, toGeminiTool(t))
+	}
+
+	cs := model.StartChat()
+	cs.History = toGeminiHistory(req.Messages[:len(req.Messages)-1])
+
+	last := req.Messages[len(req.Messages)-1]
+	resp, err := cs.SendMessage(ctx, toGeminiParts(last)...)
+	if err != nil {
+		return nil, fmt.Errorf("gemini generate content: %w", err)
+	}
+
+	return fromGeminiResponse(resp), nil
+}
+
+// ChatCompletionWithSchema satisfies gllm.SchemaAwareProvider by passing
+// schema through as Gemini's native responseSchema, instead of relying on
+// prompt-only JSON coercion.
+func (p *Google) ChatCompletionWithSchema(ctx context.Context, req gollama.RequestOptions, schema *gllm.JSONSchema) (*gollama.ResponseMessageGenerate, error) {
+	model := p.client.GenerativeModel(req.Model)
+
+	if req.System != "" {
+		model.SystemInstruction = genai.NewUserContent(genai.Text(req.System))
+	}
+
+	for _, t := range req.Tools {
+		model.Tools = append(model.Tools, toGeminiTool(t))
+	}
+
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = toGeminiSchema(schema)
+
+	cs := model.StartChat()
+	cs.History = toGeminiHistory(req.Messages[:len(req.Messages)-1])
+
+	last := req.Messages[len(req.Messages)-1]
+	resp, err := cs.SendMessage(ctx, toGeminiParts(last)...)
+	if err != nil {
+		return nil, fmt.Errorf("gemini generate content with schema: %w", err)
+	}
+
+	return fromGeminiResponse(resp), nil
+}
+
+func (p *Google) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("google: batch API not yet implemented")
+}
+
+func (p *Google) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("google: batch API not yet implemented")
+}
+
+func (p *Google) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("google: batch API not yet implemented")
+}
+
+func (p *Google) SupportsBatch() bool       { return false }
+func (p *Google) SupportsThinking() bool    { return true }
+func (p *Google) SupportsNativeTools() bool { return true }
+
+func toGeminiTool(t gollama.ToolParam) *genai.Tool {
+	return &genai.Tool{
+		FunctionDeclarations: []*genai.FunctionDeclaration{{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			Parameters:  toGeminiParamsSchema(t.Function.Parameters),
+		}},
+	}
+}
+
+// toGeminiParamsSchema translates a tool's Parameters (usually a
+// gollama.ToolFunctionParams or equivalent JSON-schema-shaped map) into a
+// genai.Schema via a JSON round-trip, since ToolFunction.Parameters is left
+// as an any to support types from the MCP library too.
+func toGeminiParamsSchema(params any) *genai.Schema {
+	if params == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil
+	}
+
+	var s gllm.JSONSchema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil
+	}
+
+	return toGeminiSchema(&s)
+}
+
+func toGeminiHistory(msgs []gollama.Message) []*genai.Content {
+	var out []*genai.Content
+	for _, m := range msgs {
+		// The system prompt is carried via model.SystemInstruction; skip any
+		// leftover role="system" message so it isn't resent as a user turn.
+		if m.Role == "system" {
+			continue
+		}
+
+		out = append(out, &genai.Content{
+			Role:  toGeminiRole(m.Role),
+			Parts: toGeminiParts(m),
+		})
+	}
+	return out
+}
+
+// toGeminiRole maps a gollama message role onto Gemini's role vocabulary:
+// "model" for the assistant's own turns, "function" for tool results, and
+// "user" for everything else.
+func toGeminiRole(role string) string {
+	switch role {
+	case "assistant":
+		return "model"
+	case "tool":
+		return "function"
+	default:
+		return "user"
+	}
+}
+
+// toGeminiParts converts a single message into the Part(s) Gemini expects:
+// a genai.FunctionResponse for a tool result, one genai.FunctionCall per
+// requested tool call for an assistant turn that made any, or plain text
+// otherwise.
+func toGeminiParts(m gollama.Message) []genai.Part {
+	if m.Role == "tool" {
+		return []genai.Part{genai.FunctionResponse{
+			Name:     m.ToolCallID,
+			Response: map[string]any{"result": m.Content},
+		}}
+	}
+
+	if len(m.ToolCalls) > 0 {
+		parts := make([]genai.Part, 0, len(m.ToolCalls))
+		for _, tc := range m.ToolCalls {
+			var args map[string]any
+			if tc.Function.Arguments != "" {
+				// Best-effort: a malformed-arguments call still needs to
+				// round-trip through history as a FunctionCall part.
+				_ = json.Unmarshal([]byte(tc.Function.Arguments), &args)
+			}
+			parts = append(parts, genai.FunctionCall{Name: tc.Function.Name, Args: args})
+		}
+		return parts
+	}
+
+	return []genai.Part{genai.Text(m.Content)}
+}
+
+// toGeminiSchema converts a gllm.JSONSchema into the subset of JSON Schema
+// genai.Schema understands for responseSchema.
+func toGeminiSchema(s *gllm.JSONSchema) *genai.Schema {
+	if s == nil {
+		return nil
+	}
+
+	gs := &genai.Schema{
+		Type:        toGeminiType(s.Type),
+		Description: s.Description,
+		Enum:        s.Enum,
+		Required:    s.Required,
+	}
+
+	if s.Items != nil {
+		gs.Items = toGeminiSchema(s.Items)
+	}
+
+	if len(s.Properties) > 0 {
+		gs.Properties = make(map[string]*genai.Schema, len(s.Properties))
+		for name, prop := range s.Properties {
+			gs.Properties[name] = toGeminiSchema(prop)
+		}
+	}
+
+	return gs
+}
+
+func toGeminiType(t string) genai.Type {
+	switch t {
+	case "object":
+		return genai.TypeObject
+	case "array":
+		return genai.TypeArray
+	case "string":
+		return genai.TypeString
+	case "boolean":
+		return genai.TypeBoolean
+	case "number":
+		return genai.TypeNumber
+	case "integer":
+		return genai.TypeInteger
+	default:
+		return genai.TypeUnspecified
+	}
+}
+
+func fromGeminiResponse(resp *genai.GenerateContentResponse) *gollama.ResponseMessageGenerate {
+	if len(resp.Candidates) == 0 {
+		return &gollama.ResponseMessageGenerate{}
+	}
+
+	candidate := resp.Candidates[0]
+
+	var content string
+	if candidate.Content != nil {
+		for _, part := range candidate.Content.Parts {
+			if txt, ok := part.(genai.Text); ok {
+				content += string(txt)
+			}
+		}
+	}
+
+	mm := gollama.Message{Role: "assistant", Content: content}
+
+	for _, fc := range candidate.FunctionCalls() {
+		args, err := json.Marshal(fc.Args)
+		if err != nil {
+			args = []byte("{}")
+		}
+
+		mm.ToolCalls = append(mm.ToolCalls, gollama.ToolCall{
+			// Gemini has no native call ID; the function name round-trips
+			// back through toGeminiParts' FunctionResponse.Name instead.
+			ID: fc.Name,
+			Function: gollama.ToolCallFunction{
+				Name:      fc.Name,
+				Arguments: string(args),
+			},
+		})
+	}
+
+	return &gollama.ResponseMessageGenerate{
+		Choices: []gollama.GenChoice{{Message: mm}},
+	}
+}
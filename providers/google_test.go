@@ -0,0 +1,127 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/whyrusleeping/gollama"
+)
+
+func TestToGeminiHistoryDropsSystemMessages(t *testing.T) {
+	history := toGeminiHistory([]gollama.Message{
+		{Role: "system", Content: "be helpful"},
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+	})
+
+	if len(history) != 2 {
+		t.Fatalf("got %d history entries, want 2 (system message should be dropped)", len(history))
+	}
+	if history[0].Role != "user" {
+		t.Errorf("history[0].Role = %q, want %q", history[0].Role, "user")
+	}
+	if history[1].Role != "model" {
+		t.Errorf("history[1].Role = %q, want %q (assistant -> model)", history[1].Role, "model")
+	}
+}
+
+func TestToGeminiHistoryRoundTripsToolCallsAndResults(t *testing.T) {
+	history := toGeminiHistory([]gollama.Message{
+		{Role: "user", Content: "what's the weather?"},
+		{
+			Role: "assistant",
+			ToolCalls: []gollama.ToolCall{
+				{ID: "get_weather", Function: gollama.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"nyc"}`}},
+			},
+		},
+		{Role: "tool", Content: "72F and sunny", ToolCallID: "get_weather"},
+	})
+
+	if len(history) != 3 {
+		t.Fatalf("got %d history entries, want 3", len(history))
+	}
+
+	callContent := history[1]
+	if callContent.Role != "model" {
+		t.Errorf("tool-call turn Role = %q, want %q", callContent.Role, "model")
+	}
+	fc, ok := callContent.Parts[0].(genai.FunctionCall)
+	if !ok {
+		t.Fatalf("tool-call turn Parts[0] = %T, want genai.FunctionCall", callContent.Parts[0])
+	}
+	if fc.Name != "get_weather" || fc.Args["city"] != "nyc" {
+		t.Errorf("FunctionCall = %+v, want Name=get_weather Args[city]=nyc", fc)
+	}
+
+	resultContent := history[2]
+	if resultContent.Role != "function" {
+		t.Errorf("tool-result turn Role = %q, want %q", resultContent.Role, "function")
+	}
+	fr, ok := resultContent.Parts[0].(genai.FunctionResponse)
+	if !ok {
+		t.Fatalf("tool-result turn Parts[0] = %T, want genai.FunctionResponse", resultContent.Parts[0])
+	}
+	if fr.Name != "get_weather" || fr.Response["result"] != "72F and sunny" {
+		t.Errorf("FunctionResponse = %+v, want Name=get_weather Response[result]=72F and sunny", fr)
+	}
+}
+
+func TestFromGeminiResponseExtractsFunctionCalls(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content: &genai.Content{
+				Role:  "model",
+				Parts: []genai.Part{genai.FunctionCall{Name: "get_weather", Args: map[string]any{"city": "nyc"}}},
+			},
+		}},
+	}
+
+	mm := fromGeminiResponse(resp).Choices[0].Message
+
+	if len(mm.ToolCalls) != 1 {
+		t.Fatalf("got %d tool calls, want 1", len(mm.ToolCalls))
+	}
+	if mm.ToolCalls[0].Function.Name != "get_weather" {
+		t.Errorf("Function.Name = %q, want %q", mm.ToolCalls[0].Function.Name, "get_weather")
+	}
+	if mm.ToolCalls[0].ID == "" {
+		t.Error("ToolCall.ID should be set (from the function name) so it can round-trip through FunctionResponse.Name")
+	}
+}
+
+func TestToGeminiToolTranslatesParameters(t *testing.T) {
+	tool := gollama.ToolParam{
+		Function: &gollama.ToolFunction{
+			Name:        "get_weather",
+			Description: "gets the weather",
+			Parameters: gollama.ToolFunctionParams{
+				Type:       "object",
+				Properties: map[string]any{"city": map[string]any{"type": "string"}},
+				Required:   []string{"city"},
+			},
+		},
+	}
+
+	out := toGeminiTool(tool)
+
+	if len(out.FunctionDeclarations) != 1 {
+		t.Fatalf("got %d function declarations, want 1", len(out.FunctionDeclarations))
+	}
+	decl := out.FunctionDeclarations[0]
+	if decl.Parameters == nil {
+		t.Fatal("Parameters is nil, want a translated schema")
+	}
+	if decl.Parameters.Type != genai.TypeObject {
+		t.Errorf("Parameters.Type = %v, want %v", decl.Parameters.Type, genai.TypeObject)
+	}
+	cityProp, ok := decl.Parameters.Properties["city"]
+	if !ok {
+		t.Fatal("missing \"city\" property")
+	}
+	if cityProp.Type != genai.TypeString {
+		t.Errorf("city.Type = %v, want %v", cityProp.Type, genai.TypeString)
+	}
+	if len(decl.Parameters.Required) != 1 || decl.Parameters.Required[0] != "city" {
+		t.Errorf("Required = %v, want [city]", decl.Parameters.Required)
+	}
+}
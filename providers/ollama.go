@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// Ollama adapts a local (or remote) Ollama server's /api/chat endpoint to
+// the gllm.Provider interface. Unlike the other providers it speaks plain
+// REST, so no SDK dependency is required.
+type Ollama struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewOllama builds an Ollama provider pointed at baseURL, e.g.
+// "http://localhost:11434".
+func NewOllama(baseURL string) *Ollama {
+	return &Ollama{
+		BaseURL:    baseURL,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+type ollamaChatRequest struct {
+	Model    string            `json:"model"`
+	Messages []gollama.Message `json:"messages"`
+	Stream   bool              `json:"stream"`
+}
+
+type ollamaChatResponse struct {
+	Message gollama.Message `json:"message"`
+}
+
+func (p *Ollama) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	var msgs []gollama.Message
+	for _, m := range req.Messages {
+		// req.System below already carries the system prompt; skip any
+		// leftover role="system" message so it isn't sent twice.
+		if m.Role == "system" && req.System != "" {
+			continue
+		}
+		msgs = append(msgs, m)
+	}
+	if req.System != "" {
+		msgs = append([]gollama.Message{{Role: "system", Content: req.System}}, msgs...)
+	}
+
+	body, err := json.Marshal(ollamaChatRequest{
+		Model:    req.Model,
+		Messages: msgs,
+		Stream:   false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ollama request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building ollama request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama chat request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama chat request: unexpected status %s", resp.Status)
+	}
+
+	var ocr ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ocr); err != nil {
+		return nil, fmt.Errorf("decoding ollama response: %w", err)
+	}
+
+	return &gollama.ResponseMessageGenerate{
+		Choices: []gollama.GenChoice{{Message: ocr.Message}},
+	}, nil
+}
+
+func (p *Ollama) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("ollama: batch API not supported")
+}
+
+func (p *Ollama) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("ollama: batch API not supported")
+}
+
+func (p *Ollama) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("ollama: batch API not supported")
+}
+
+func (p *Ollama) SupportsBatch() bool       { return false }
+func (p *Ollama) SupportsThinking() bool    { return false }
+func (p *Ollama) SupportsNativeTools() bool { return false }
@@ -0,0 +1,182 @@
+// Package providers contains concrete Provider implementations for the
+// various LLM backends gllm can target, following the interface defined in
+// the root gllm package.
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/whyrusleeping/gllm"
+	"github.com/whyrusleeping/gollama"
+)
+
+// OpenAI adapts an OpenAI (or OpenAI-compatible) chat completions client to
+// the gllm.Provider interface.
+type OpenAI struct {
+	client *openai.Client
+}
+
+// NewOpenAI builds an OpenAI provider from an API key. Use NewOpenAICompatible
+// to point at Azure OpenAI or other compatible endpoints.
+func NewOpenAI(apiKey string) *OpenAI {
+	return &OpenAI{client: openai.NewClient(apiKey)}
+}
+
+// NewOpenAICompatible builds an OpenAI provider against a custom base URL.
+func NewOpenAICompatible(apiKey, baseURL string) *OpenAI {
+	cfg := openai.DefaultConfig(apiKey)
+	cfg.BaseURL = baseURL
+	return &OpenAI{client: openai.NewClientWithConfig(cfg)}
+}
+
+func (p *OpenAI) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	oreq := openai.ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: toOpenAIMessages(req),
+	}
+
+	if len(req.Tools) > 0 {
+		oreq.Tools = toOpenAITools(req.Tools)
+	}
+
+	resp, err := p.client.CreateChatCompletion(ctx, oreq)
+	if err != nil {
+		return nil, fmt.Errorf("openai chat completion: %w", err)
+	}
+
+	return fromOpenAIResponse(resp), nil
+}
+
+// ChatCompletionWithSchema satisfies gllm.SchemaAwareProvider by passing
+// schema through as an OpenAI json_schema response_format, instead of relying
+// on prompt-only JSON coercion.
+func (p *OpenAI) ChatCompletionWithSchema(ctx context.Context, req gollama.RequestOptions, schema *gllm.JSONSchema) (*gollama.ResponseMessageGenerate, error) {
+	oreq := openai.ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: toOpenAIMessages(req),
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "output",
+				Schema: schema,
+				Strict: true,
+			},
+		},
+	}
+
+	if len(req.Tools) > 0 {
+		oreq.Tools = toOpenAITools(req.Tools)
+	}
+
+	resp, err := p.client.CreateChatCompletion(ctx, oreq)
+	if err != nil {
+		return nil, fmt.Errorf("openai chat completion with schema: %w", err)
+	}
+
+	return fromOpenAIResponse(resp), nil
+}
+
+func (p *OpenAI) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("openai: batch API not yet implemented")
+}
+
+func (p *OpenAI) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("openai: batch API not yet implemented")
+}
+
+func (p *OpenAI) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("openai: batch API not yet implemented")
+}
+
+func (p *OpenAI) SupportsBatch() bool       { return false }
+func (p *OpenAI) SupportsThinking() bool    { return false }
+func (p *OpenAI) SupportsNativeTools() bool { return true }
+
+func toOpenAIMessages(req gollama.RequestOptions) []openai.ChatCompletionMessage {
+	var msgs []openai.ChatCompletionMessage
+	if req.System != "" {
+		msgs = append(msgs, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: req.System,
+		})
+	}
+
+	for _, m := range req.Messages {
+		// req.System above already carries the system prompt; skip any
+		// leftover role="system" message so it isn't sent twice.
+		if m.Role == "system" && req.System != "" {
+			continue
+		}
+
+		msgs = append(msgs, openai.ChatCompletionMessage{
+			Role:       m.Role,
+			Content:    m.Content,
+			ToolCalls:  toOpenAIToolCalls(m.ToolCalls),
+			ToolCallID: m.ToolCallID,
+		})
+	}
+
+	return msgs
+}
+
+func toOpenAIToolCalls(calls []gollama.ToolCall) []openai.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	out := make([]openai.ToolCall, 0, len(calls))
+	for _, tc := range calls {
+		out = append(out, openai.ToolCall{
+			ID:   tc.ID,
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+	return out
+}
+
+func toOpenAITools(tools []gollama.ToolParam) []openai.Tool {
+	var out []openai.Tool
+	for _, t := range tools {
+		out = append(out, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  t.Function.Parameters,
+			},
+		})
+	}
+	return out
+}
+
+func fromOpenAIResponse(resp openai.ChatCompletionResponse) *gollama.ResponseMessageGenerate {
+	if len(resp.Choices) == 0 {
+		return &gollama.ResponseMessageGenerate{}
+	}
+
+	choice := resp.Choices[0]
+	mm := gollama.Message{
+		Role:    choice.Message.Role,
+		Content: choice.Message.Content,
+	}
+
+	for _, tc := range choice.Message.ToolCalls {
+		mm.ToolCalls = append(mm.ToolCalls, gollama.ToolCall{
+			ID: tc.ID,
+			Function: gollama.ToolCallFunction{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+
+	return &gollama.ResponseMessageGenerate{
+		Choices: []gollama.GenChoice{{Message: mm}},
+	}
+}
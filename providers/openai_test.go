@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/whyrusleeping/gollama"
+)
+
+func TestToOpenAIMessagesDropsLeftoverSystemMessage(t *testing.T) {
+	req := gollama.RequestOptions{
+		System: "be helpful",
+		Messages: []gollama.Message{
+			{Role: "system", Content: "be helpful"},
+			{Role: "user", Content: "hi"},
+		},
+	}
+
+	msgs := toOpenAIMessages(req)
+
+	var systemCount int
+	for _, m := range msgs {
+		if m.Role == openai.ChatMessageRoleSystem {
+			systemCount++
+		}
+	}
+	if systemCount != 1 {
+		t.Errorf("got %d system messages, want exactly 1 (req.System, with the leftover one in req.Messages dropped)", systemCount)
+	}
+}
+
+func TestToOpenAIMessagesCarriesToolCalls(t *testing.T) {
+	req := gollama.RequestOptions{
+		Messages: []gollama.Message{
+			{Role: "user", Content: "what's the weather?"},
+			{
+				Role: "assistant",
+				ToolCalls: []gollama.ToolCall{
+					{ID: "call_1", Type: "function", Function: gollama.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"nyc"}`}},
+				},
+			},
+			{Role: "tool", Content: "72F and sunny", ToolCallID: "call_1"},
+		},
+	}
+
+	msgs := toOpenAIMessages(req)
+
+	var assistantMsg openai.ChatCompletionMessage
+	for _, m := range msgs {
+		if m.Role == openai.ChatMessageRoleAssistant {
+			assistantMsg = m
+		}
+	}
+
+	if len(assistantMsg.ToolCalls) != 1 {
+		t.Fatalf("assistant message has %d tool calls, want 1", len(assistantMsg.ToolCalls))
+	}
+	if assistantMsg.ToolCalls[0].ID != "call_1" {
+		t.Errorf("tool call ID = %q, want %q", assistantMsg.ToolCalls[0].ID, "call_1")
+	}
+	if assistantMsg.ToolCalls[0].Function.Name != "get_weather" {
+		t.Errorf("tool call function name = %q, want %q", assistantMsg.ToolCalls[0].Function.Name, "get_weather")
+	}
+}
+
+func TestToOpenAIToolsTranslatesParameters(t *testing.T) {
+	tools := []gollama.ToolParam{{
+		Type: "function",
+		Function: &gollama.ToolFunction{
+			Name:        "get_weather",
+			Description: "gets the weather",
+			Parameters:  map[string]any{"type": "object"},
+		},
+	}}
+
+	out := toOpenAITools(tools)
+
+	if len(out) != 1 {
+		t.Fatalf("got %d tools, want 1", len(out))
+	}
+	if out[0].Function.Name != "get_weather" {
+		t.Errorf("Name = %q, want %q", out[0].Function.Name, "get_weather")
+	}
+	if out[0].Function.Parameters == nil {
+		t.Error("Parameters should carry through, got nil")
+	}
+}
@@ -0,0 +1,109 @@
+package gllm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// fakeRepairProvider returns malformed JSON for its first badResponses
+// calls, then a valid response, so the MaxRepairAttempts round-trip in
+// ModelCallStructured can be exercised without a real backend.
+type fakeRepairProvider struct {
+	badResponses int
+	calls        int
+}
+
+func (f *fakeRepairProvider) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	f.calls++
+	if f.calls <= f.badResponses {
+		return &gollama.ResponseMessageGenerate{
+			Choices: []gollama.GenChoice{{Message: gollama.Message{Role: "assistant", Content: `{"answer": not json`}}},
+		}, nil
+	}
+	return &gollama.ResponseMessageGenerate{
+		Choices: []gollama.GenChoice{{Message: gollama.Message{Role: "assistant", Content: `{"answer":"fixed"}`}}},
+	}, nil
+}
+
+func (f *fakeRepairProvider) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeRepairProvider) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeRepairProvider) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeRepairProvider) SupportsBatch() bool       { return false }
+func (f *fakeRepairProvider) SupportsThinking() bool    { return false }
+func (f *fakeRepairProvider) SupportsNativeTools() bool { return false }
+
+type repairTestOutput struct {
+	Answer string `json:"answer"`
+}
+
+func TestModelCallStructuredRepairsMalformedJSON(t *testing.T) {
+	provider := &fakeRepairProvider{badResponses: 1}
+	req := &StructuredRequest[repairTestOutput]{
+		Model:             "test-model",
+		MaxRepairAttempts: 2,
+	}
+
+	resp, err := ModelCallStructured(NewClientWithProvider(provider), context.Background(), req)
+	if err != nil {
+		t.Fatalf("ModelCallStructured: %v", err)
+	}
+	if resp.RepairsUsed != 1 {
+		t.Errorf("RepairsUsed = %d, want 1", resp.RepairsUsed)
+	}
+	if resp.Output == nil || resp.Output.Answer != "fixed" {
+		t.Fatalf("Output = %+v, want Answer = \"fixed\"", resp.Output)
+	}
+
+	var sawRepairPrompt bool
+	for _, m := range resp.InputMessages {
+		if strings.Contains(m.Content, "could not be parsed as JSON") {
+			sawRepairPrompt = true
+		}
+	}
+	if !sawRepairPrompt {
+		t.Error("expected a repair-request message to be appended to InputMessages")
+	}
+}
+
+func TestModelCallStructuredGivesUpAfterMaxRepairAttempts(t *testing.T) {
+	provider := &fakeRepairProvider{badResponses: 5}
+	req := &StructuredRequest[repairTestOutput]{
+		Model:             "test-model",
+		MaxRepairAttempts: 2,
+	}
+
+	_, err := ModelCallStructured(NewClientWithProvider(provider), context.Background(), req)
+	if err == nil {
+		t.Fatal("expected an error once MaxRepairAttempts is exhausted")
+	}
+	// 1 initial attempt + 2 repair attempts = 3 calls total.
+	if provider.calls != 3 {
+		t.Errorf("provider.calls = %d, want 3 (initial + MaxRepairAttempts)", provider.calls)
+	}
+}
+
+func TestModelCallStructuredFailsImmediatelyWithNoRepairAttempts(t *testing.T) {
+	provider := &fakeRepairProvider{badResponses: 1}
+	req := &StructuredRequest[repairTestOutput]{Model: "test-model"}
+
+	_, err := ModelCallStructured(NewClientWithProvider(provider), context.Background(), req)
+	if err == nil {
+		t.Fatal("expected an error when MaxRepairAttempts is zero (the default)")
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider.calls = %d, want 1 (no repair round-trip)", provider.calls)
+	}
+}
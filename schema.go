@@ -0,0 +1,178 @@
+package gllm
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// SchemaAwareProvider is implemented by providers whose API accepts a
+// native JSON Schema for structured output (OpenAI's response_format,
+// Gemini's responseSchema). When a provider implements this,
+// ModelCallStructured passes the generated schema through directly instead
+// of relying solely on prompt-only JSON coercion.
+type SchemaAwareProvider interface {
+	Provider
+	ChatCompletionWithSchema(ctx context.Context, req gollama.RequestOptions, schema *JSONSchema) (*gollama.ResponseMessageGenerate, error)
+}
+
+// schemaFor generates a JSONSchema for obj's type, unless obj implements
+// customOutputSpec (in which case the caller controls the output spec
+// entirely, and no schema is available to pass through to providers).
+func schemaFor(obj any) (*JSONSchema, bool) {
+	if _, ok := obj.(customOutputSpec); ok {
+		return nil, false
+	}
+	return generateSchema(reflect.TypeOf(obj)), true
+}
+
+// JSONSchema is a (deliberately small) subset of JSON Schema sufficient to
+// describe T's shape to a model, and to hand to providers whose APIs accept
+// a native JSON Schema for structured output (OpenAI's response_format,
+// Gemini's responseSchema).
+type JSONSchema struct {
+	Type        string                 `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+}
+
+// MarshalJSON satisfies json.Marshaler so a *JSONSchema can be handed
+// directly to APIs that require one for their response-format schema field
+// (e.g. go-openai's ChatCompletionResponseFormatJSONSchema.Schema).
+func (s *JSONSchema) MarshalJSON() ([]byte, error) {
+	type alias JSONSchema
+	return json.Marshal((*alias)(s))
+}
+
+// Schema generates a JSONSchema for T by walking its reflect.Type, honoring
+// `json:"..."` tags for field naming/omission plus `desc:"..."`,
+// `enum:"a,b,c"`, and `required:"true"` struct tags for richer type
+// information than a zero-value marshal can provide.
+func Schema[T any]() *JSONSchema {
+	return generateSchema(reflect.TypeOf(*new(T)))
+}
+
+func generateSchema(t reflect.Type) *JSONSchema {
+	return generateSchemaPath(t, map[reflect.Type]bool{})
+}
+
+// generateSchemaPath walks t like generateSchema, but threads the set of
+// struct types already on the current recursion path so self-referential
+// types (e.g. type Node struct { Children []*Node }) terminate instead of
+// recursing forever.
+func generateSchemaPath(t reflect.Type, path map[reflect.Type]bool) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return generateStructSchema(t, path)
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{
+			Type:  "array",
+			Items: generateSchemaPath(t.Elem(), path),
+		}
+	case reflect.Map:
+		return &JSONSchema{Type: "object"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	default:
+		return &JSONSchema{Type: "string"}
+	}
+}
+
+func generateStructSchema(t reflect.Type, path map[reflect.Type]bool) *JSONSchema {
+	// t is already being expanded higher up this same recursion path (a
+	// self-referential type): stop here with a bare object rather than
+	// recursing forever. Siblings that reach t via a different branch are
+	// unaffected, since t is removed from path once this call returns.
+	if path[t] {
+		return &JSONSchema{Type: "object"}
+	}
+	path[t] = true
+	defer delete(path, t)
+
+	s := &JSONSchema{
+		Type:       "object",
+		Properties: make(map[string]*JSONSchema),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+
+		name, omitempty := fieldName(f)
+		if name == "-" {
+			continue
+		}
+
+		fs := generateSchemaPath(f.Type, path)
+		if desc := f.Tag.Get("desc"); desc != "" {
+			fs.Description = desc
+		}
+		if enum := f.Tag.Get("enum"); enum != "" {
+			fs.Enum = strings.Split(enum, ",")
+		}
+
+		s.Properties[name] = fs
+
+		if f.Tag.Get("required") == "true" || !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s
+}
+
+// fieldName applies the same `json:"name,omitempty"` parsing rules
+// encoding/json uses, defaulting to the Go field name when there's no tag.
+func fieldName(f reflect.StructField) (name string, omitempty bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// renderOutputSpec describes T's expected output to the model: either via
+// the customOutputSpec escape hatch, or a generated JSON Schema document.
+func renderOutputSpec(obj any) (string, error) {
+	if cos, ok := obj.(customOutputSpec); ok {
+		return cos.DescribeType(), nil
+	}
+
+	schema := generateSchema(reflect.TypeOf(obj))
+	b, err := json.MarshalIndent(schema, "", "  ")
+	return string(b), err
+}
+
+type customOutputSpec interface {
+	DescribeType() string
+}
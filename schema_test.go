@@ -0,0 +1,140 @@
+package gllm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaTestChild struct {
+	Name string `json:"name"`
+}
+
+type schemaTestStruct struct {
+	Name     string            `json:"name" desc:"the name"`
+	Tags     []string          `json:"tags,omitempty" enum:"a,b,c"`
+	Child    schemaTestChild   `json:"child"`
+	Children []schemaTestChild `json:"children,omitempty"`
+	Count    int               `json:"count,omitempty" required:"true"`
+	Bare     string
+	hidden   string
+}
+
+func TestGenerateStructSchema(t *testing.T) {
+	s := generateSchema(reflect.TypeOf(schemaTestStruct{}))
+
+	if s.Type != "object" {
+		t.Fatalf("Type = %q, want %q", s.Type, "object")
+	}
+
+	nameProp, ok := s.Properties["name"]
+	if !ok {
+		t.Fatal("missing \"name\" property")
+	}
+	if nameProp.Type != "string" {
+		t.Errorf("name.Type = %q, want %q", nameProp.Type, "string")
+	}
+	if nameProp.Description != "the name" {
+		t.Errorf("name.Description = %q, want %q", nameProp.Description, "the name")
+	}
+
+	tagsProp, ok := s.Properties["tags"]
+	if !ok {
+		t.Fatal("missing \"tags\" property")
+	}
+	if tagsProp.Type != "array" || tagsProp.Items == nil || tagsProp.Items.Type != "string" {
+		t.Errorf("tags schema = %+v, want array of string", tagsProp)
+	}
+	if got := tagsProp.Enum; len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("tags.Enum = %v, want [a b c]", got)
+	}
+
+	childProp, ok := s.Properties["child"]
+	if !ok || childProp.Type != "object" {
+		t.Fatalf("child property = %+v, want object", childProp)
+	}
+
+	if _, ok := s.Properties["hidden"]; ok {
+		t.Error("unexported field \"hidden\" should not appear in the schema")
+	}
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	// name/child: plain json tag, no omitempty -> required.
+	if !required["name"] {
+		t.Error("\"name\" should be required (no omitempty)")
+	}
+	if !required["child"] {
+		t.Error("\"child\" should be required (no omitempty)")
+	}
+	// tags/children: omitempty, no explicit required tag -> not required.
+	if required["tags"] {
+		t.Error("\"tags\" should not be required (omitempty)")
+	}
+	if required["children"] {
+		t.Error("\"children\" should not be required (omitempty)")
+	}
+	// count: omitempty but explicit required:"true" -> required.
+	if !required["count"] {
+		t.Error("\"count\" should be required (explicit required:\"true\" tag)")
+	}
+	// Bare: no json tag at all, no omitempty -> required (defaults to its Go name).
+	if !required["Bare"] {
+		t.Error("\"Bare\" should be required (no tag means no omitempty)")
+	}
+}
+
+type schemaTestRecNode struct {
+	Name     string               `json:"name"`
+	Children []*schemaTestRecNode `json:"children,omitempty"`
+	Parent   *schemaTestRecNode   `json:"parent,omitempty"`
+}
+
+func TestGenerateSchemaTerminatesOnSelfReferentialType(t *testing.T) {
+	done := make(chan *JSONSchema, 1)
+	go func() {
+		done <- generateSchema(reflect.TypeOf(schemaTestRecNode{}))
+	}()
+
+	select {
+	case s := <-done:
+		if s.Type != "object" {
+			t.Fatalf("Type = %q, want %q", s.Type, "object")
+		}
+		childrenProp, ok := s.Properties["children"]
+		if !ok || childrenProp.Type != "array" || childrenProp.Items == nil {
+			t.Fatalf("children schema = %+v, want array of object", childrenProp)
+		}
+		// The recursive occurrence should stop expanding rather than
+		// recursing into children/parent again.
+		if _, ok := childrenProp.Items.Properties["children"]; ok {
+			t.Error("self-referential type should not expand past the first occurrence on the path")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("generateSchema did not terminate on a self-referential type")
+	}
+}
+
+func TestJSONSchemaMarshalJSON(t *testing.T) {
+	s := &JSONSchema{Type: "string", Description: "a string"}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if out["type"] != "string" {
+		t.Errorf("type = %v, want \"string\"", out["type"])
+	}
+	if out["description"] != "a string" {
+		t.Errorf("description = %v, want \"a string\"", out["description"])
+	}
+}
@@ -0,0 +1,191 @@
+package gllm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// StreamChunkType identifies what a StreamChunk carries.
+type StreamChunkType string
+
+const (
+	// StreamChunkToken is a raw token (or token fragment) from the model.
+	StreamChunkToken StreamChunkType = "token"
+
+	// StreamChunkPartialOutput carries the parse of the typed output so
+	// far, for progress UIs. It is only emitted once the accumulated buffer
+	// decodes as complete JSON, so PartialOutput is never nil.
+	StreamChunkPartialOutput StreamChunkType = "partial_output"
+)
+
+// StreamChunk is a single incremental update emitted while a streaming
+// structured call is in flight.
+type StreamChunk struct {
+	Type StreamChunkType
+
+	// Token is set for StreamChunkToken.
+	Token string
+
+	// PartialOutput is set for StreamChunkPartialOutput, best-effort
+	// decoded from the buffer accumulated so far.
+	PartialOutput any
+}
+
+// ModelCallStructuredStream mirrors ModelCallStructured, but pushes
+// incremental tokens onto chunks as they arrive from the provider, while
+// still accumulating and returning the fully parsed *T once the response
+// completes. Tool calls, like ModelCallStructured, are only visible once
+// the provider returns its final response — gollama's streaming contract
+// (onDelta snapshots) has no concept of incremental tool-call deltas.
+//
+// chunks is closed by this function once the call finishes, whether it
+// succeeds or errors.
+func ModelCallStructuredStream[T any](c *Client, ctx context.Context, req *StructuredRequest[T], chunks chan<- StreamChunk) (*Response[T], error) {
+	defer close(chunks)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ospec, err := renderOutputSpec(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []gollama.Message
+	if len(req.MessagePrefill) > 0 {
+		msgs = req.MessagePrefill
+	} else if req.System != "" {
+		msgs = append(msgs, gollama.Message{
+			Role:    "system",
+			Content: req.System,
+		})
+	}
+
+	templ, err := template.New("prompt").Parse(req.getStructuredCallPrompt())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := templ.Execute(buf, &structuredCallParams{
+		OutputTemplate: ospec,
+		Prompt:         req.Prompt,
+		Context:        req.Context,
+		MaxToolCalls:   req.MaxToolCalls,
+	}); err != nil {
+		return nil, fmt.Errorf("prompt template execution failed: %w", err)
+	}
+
+	m := gollama.Message{
+		Role:    "user",
+		Content: buf.String(),
+		Images:  req.Images,
+	}
+	msgs = append(msgs, m)
+
+	var tooldefs []gollama.ToolParam
+	for _, t := range req.Tools {
+		tooldefs = append(tooldefs, t.ApiDef())
+	}
+
+	think := true
+	if req.Think != nil {
+		think = *req.Think
+	}
+
+	sp, ok := c.provider.(StreamingProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider does not support streaming")
+	}
+
+	repairsUsed := 0
+
+	for {
+		glreq := gollama.RequestOptions{
+			Model:    req.Model,
+			System:   req.System,
+			Think:    think,
+			Messages: msgs,
+			Stream:   true,
+		}
+
+		if req.MaxToolCalls > 0 {
+			glreq.Tools = tooldefs
+			glreq.ToolChoice = "auto"
+		}
+
+		// onDelta receives snapshots (the full accumulated text so far, per
+		// gollama.Client.TurnStream's contract), so only the newly-arrived
+		// suffix is forwarded as a token chunk.
+		streamBuf := new(bytes.Buffer)
+		resp, err := sp.ChatCompletionStream(ctx, glreq, func(text string) {
+			if len(text) <= streamBuf.Len() {
+				return
+			}
+			delta := text[streamBuf.Len():]
+			streamBuf.WriteString(delta)
+			chunks <- StreamChunk{Type: StreamChunkToken, Token: delta}
+
+			if partial, ok := tryPartialDecode[T](streamBuf.String()); ok {
+				chunks <- StreamChunk{Type: StreamChunkPartialOutput, PartialOutput: partial}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("provider returned no choices")
+		}
+
+		var result *Response[T]
+		var done bool
+		result, msgs, repairsUsed, done, err = handleStructuredResponse(ctx, c, req, ospec, resp, msgs, repairsUsed)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return result, nil
+		}
+	}
+}
+
+// StreamingProvider is implemented by providers that can stream completions
+// incrementally rather than returning the whole response at once.
+type StreamingProvider interface {
+	Provider
+
+	// ChatCompletionStream issues a streaming completion request. onDelta is
+	// invoked with the full accumulated assistant text so far each time new
+	// text arrives (snapshot semantics, matching gollama.Client.TurnStream),
+	// serially and possibly zero times (e.g. a turn that only produces tool
+	// calls), and the fully assembled response is returned once the stream
+	// ends. Tool calls, if any, are only available on the returned response,
+	// not incrementally. Cancelling ctx should stop the in-flight stream.
+	ChatCompletionStream(ctx context.Context, req gollama.RequestOptions, onDelta func(text string)) (*gollama.ResponseMessageGenerate, error)
+}
+
+// tryPartialDecode strips code fences and any leading comment from buf and
+// decodes the rest, for emitting as StreamChunkPartialOutput. The whole
+// remainder must be complete, valid JSON; a truncated JSON prefix is not
+// decoded. It returns ok=false while the buffer isn't parseable yet, which
+// is expected for most of the stream.
+func tryPartialDecode[T any](buf string) (*T, bool) {
+	candidate := cleanJsonOutput(buf)
+	_, jsonout := extractJSONAndComment(candidate)
+	if jsonout == "" {
+		return nil, false
+	}
+
+	var outv T
+	if err := json.Unmarshal([]byte(jsonout), &outv); err != nil {
+		return nil, false
+	}
+
+	return &outv, true
+}
@@ -0,0 +1,103 @@
+package gllm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// fakeStreamProvider is a minimal Provider/StreamingProvider that replays a
+// fixed sequence of onDelta snapshots before returning resp, so
+// ModelCallStructuredStream's chunk forwarding can be exercised without a
+// real backend.
+type fakeStreamProvider struct {
+	snapshots []string
+	resp      *gollama.ResponseMessageGenerate
+}
+
+func (f *fakeStreamProvider) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeStreamProvider) ChatCompletionStream(ctx context.Context, req gollama.RequestOptions, onDelta func(text string)) (*gollama.ResponseMessageGenerate, error) {
+	for _, s := range f.snapshots {
+		onDelta(s)
+	}
+	return f.resp, nil
+}
+
+func (f *fakeStreamProvider) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeStreamProvider) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeStreamProvider) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeStreamProvider) SupportsBatch() bool       { return false }
+func (f *fakeStreamProvider) SupportsThinking() bool    { return false }
+func (f *fakeStreamProvider) SupportsNativeTools() bool { return false }
+
+type streamTestOutput struct {
+	Answer string `json:"answer"`
+}
+
+func TestModelCallStructuredStreamForwardsTokenDeltas(t *testing.T) {
+	provider := &fakeStreamProvider{
+		snapshots: []string{`{"ans`, `{"answer":"hi"}`},
+		resp: &gollama.ResponseMessageGenerate{
+			Choices: []gollama.GenChoice{{Message: gollama.Message{Role: "assistant", Content: `{"answer":"hi"}`}}},
+		},
+	}
+	c := NewClientWithProvider(provider)
+
+	chunks := make(chan StreamChunk, 16)
+	resp, err := ModelCallStructuredStream(c, context.Background(), &StructuredRequest[streamTestOutput]{Model: "test-model"}, chunks)
+	if err != nil {
+		t.Fatalf("ModelCallStructuredStream: %v", err)
+	}
+
+	var tokens []string
+	var sawPartial bool
+	for chunk := range chunks {
+		switch chunk.Type {
+		case StreamChunkToken:
+			tokens = append(tokens, chunk.Token)
+		case StreamChunkPartialOutput:
+			sawPartial = true
+		}
+	}
+
+	if len(tokens) != 2 {
+		t.Fatalf("got %d token chunks, want 2 (only the newly-arrived suffix of each snapshot)", len(tokens))
+	}
+	if tokens[0] != `{"ans` {
+		t.Errorf("tokens[0] = %q, want %q", tokens[0], `{"ans`)
+	}
+	if tokens[1] != `wer":"hi"}` {
+		t.Errorf("tokens[1] = %q, want %q (only the delta beyond the first snapshot)", tokens[1], `wer":"hi"}`)
+	}
+	if !sawPartial {
+		t.Error("expected at least one StreamChunkPartialOutput once the buffer became valid JSON")
+	}
+
+	if resp.Output == nil || resp.Output.Answer != "hi" {
+		t.Fatalf("Output = %+v, want Answer = \"hi\"", resp.Output)
+	}
+}
+
+func TestModelCallStructuredStreamRequiresStreamingProvider(t *testing.T) {
+	c := NewClientWithProvider(&fakeBatchProvider{})
+
+	chunks := make(chan StreamChunk, 1)
+	_, err := ModelCallStructuredStream(c, context.Background(), &StructuredRequest[streamTestOutput]{Model: "test-model"}, chunks)
+	if err == nil {
+		t.Fatal("expected an error when the provider does not implement StreamingProvider")
+	}
+}
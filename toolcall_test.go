@@ -0,0 +1,144 @@
+package gllm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/whyrusleeping/gollama"
+)
+
+// fakeToolCallProvider returns a single tool call on its first
+// ChatCompletion, then the final JSON answer on every call after, so
+// ModelCallStructured's tool-approval/execution loop can be exercised
+// without a real backend.
+type fakeToolCallProvider struct {
+	calls int
+}
+
+func (f *fakeToolCallProvider) ChatCompletion(ctx context.Context, req gollama.RequestOptions) (*gollama.ResponseMessageGenerate, error) {
+	f.calls++
+	if f.calls == 1 {
+		return &gollama.ResponseMessageGenerate{
+			Choices: []gollama.GenChoice{{Message: gollama.Message{
+				Role: "assistant",
+				ToolCalls: []gollama.ToolCall{
+					{ID: "call_1", Function: gollama.ToolCallFunction{Name: "echo", Arguments: `{"msg":"hi"}`}},
+				},
+			}}},
+		}, nil
+	}
+	return &gollama.ResponseMessageGenerate{
+		Choices: []gollama.GenChoice{{Message: gollama.Message{Role: "assistant", Content: `{"answer":"done"}`}}},
+	}, nil
+}
+
+func (f *fakeToolCallProvider) CreateBatch(ctx context.Context, req gollama.CreateBatchRequest) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeToolCallProvider) GetBatch(ctx context.Context, batchID string) (*gollama.Batch, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeToolCallProvider) GetBatchResults(ctx context.Context, batchID string) ([]gollama.BatchResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeToolCallProvider) SupportsBatch() bool       { return false }
+func (f *fakeToolCallProvider) SupportsThinking() bool    { return false }
+func (f *fakeToolCallProvider) SupportsNativeTools() bool { return true }
+
+type toolCallTestOutput struct {
+	Answer string `json:"answer"`
+}
+
+func echoTool(called *bool) *gollama.Tool {
+	return &gollama.Tool{
+		Name: "echo",
+		Call: func(ctx context.Context, params any) (*gollama.ToolResult, error) {
+			*called = true
+			return &gollama.ToolResult{Content: "echoed"}, nil
+		},
+	}
+}
+
+func TestModelCallStructuredAutoExecutesTools(t *testing.T) {
+	var toolRan bool
+	req := &StructuredRequest[toolCallTestOutput]{
+		Model:        "test-model",
+		MaxToolCalls: 1,
+		Tools:        []*gollama.Tool{echoTool(&toolRan)},
+	}
+
+	c := NewClientWithProvider(&fakeToolCallProvider{})
+	resp, err := ModelCallStructured(c, context.Background(), req)
+	if err != nil {
+		t.Fatalf("ModelCallStructured: %v", err)
+	}
+	if !toolRan {
+		t.Error("expected the tool's Call func to run")
+	}
+	if resp.Output == nil || resp.Output.Answer != "done" {
+		t.Fatalf("Output = %+v, want Answer = \"done\"", resp.Output)
+	}
+}
+
+func TestModelCallStructuredApproveToolCallCanVeto(t *testing.T) {
+	var toolRan bool
+	var sawEvent ToolCallEvent
+	req := &StructuredRequest[toolCallTestOutput]{
+		Model:        "test-model",
+		MaxToolCalls: 1,
+		Tools:        []*gollama.Tool{echoTool(&toolRan)},
+		ApproveToolCall: func(ctx context.Context, toolName, argsJSON string) (bool, string, error) {
+			return false, "denied by policy", nil
+		},
+		OnToolCall: func(event ToolCallEvent) {
+			sawEvent = event
+		},
+	}
+
+	c := NewClientWithProvider(&fakeToolCallProvider{})
+	resp, err := ModelCallStructured(c, context.Background(), req)
+	if err != nil {
+		t.Fatalf("ModelCallStructured: %v", err)
+	}
+	if toolRan {
+		t.Error("vetoed tool call should not have run the tool's Call func")
+	}
+	if sawEvent.Approved {
+		t.Error("OnToolCall event should report Approved = false")
+	}
+	if sawEvent.Result != "denied by policy" {
+		t.Errorf("OnToolCall event Result = %q, want the override result", sawEvent.Result)
+	}
+	if resp.Output == nil || resp.Output.Answer != "done" {
+		t.Fatalf("Output = %+v, want Answer = \"done\"", resp.Output)
+	}
+}
+
+func TestModelCallStructuredManualToolLoopReturnsPending(t *testing.T) {
+	var toolRan bool
+	req := &StructuredRequest[toolCallTestOutput]{
+		Model:            "test-model",
+		MaxToolCalls:     1,
+		Tools:            []*gollama.Tool{echoTool(&toolRan)},
+		AutoExecuteTools: BoolPtr(false),
+	}
+
+	c := NewClientWithProvider(&fakeToolCallProvider{})
+	resp, err := ModelCallStructured(c, context.Background(), req)
+	if err != nil {
+		t.Fatalf("ModelCallStructured: %v", err)
+	}
+	if toolRan {
+		t.Error("AutoExecuteTools = false should not run the tool itself")
+	}
+	if resp.Output != nil {
+		t.Errorf("Output = %+v, want nil when returning PendingToolCalls", resp.Output)
+	}
+	if len(resp.PendingToolCalls) != 1 || resp.PendingToolCalls[0].ToolName != "echo" {
+		t.Fatalf("PendingToolCalls = %+v, want one pending call to \"echo\"", resp.PendingToolCalls)
+	}
+}